@@ -0,0 +1,126 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	golog "gopkg.in/op/go-logging.v1"
+)
+
+// Logger is the logging interface used throughout go-jira.  It is
+// intentionally small so that callers embedding go-jira as a library can
+// supply their own implementation via SetLogger instead of being stuck
+// with go-jira's default, which previously called os.Exit on errors that
+// a library caller would rather handle itself.
+type Logger interface {
+	Debug(args ...interface{})
+	Debugf(format string, args ...interface{})
+	Info(args ...interface{})
+	Infof(format string, args ...interface{})
+	Warn(args ...interface{})
+	Warnf(format string, args ...interface{})
+	Error(args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// loggerBox is the single concrete type stored in atomicLogger.v.
+// atomic.Value panics if two different concrete types are ever stored
+// in it, so SetLogger can't store a Logger directly since each caller
+// might pass a different implementation; boxing it keeps the stored
+// type constant.
+type loggerBox struct {
+	l Logger
+}
+
+// atomicLogger forwards every Logger call to whatever Logger was last
+// stored in it, so that SetLogger can swap the package-wide logger
+// while other goroutines are mid-call without a data race.
+type atomicLogger struct {
+	v atomic.Value
+}
+
+func (l *atomicLogger) get() Logger { return l.v.Load().(loggerBox).l }
+
+func (l *atomicLogger) Debug(args ...interface{})                 { l.get().Debug(args...) }
+func (l *atomicLogger) Debugf(format string, args ...interface{}) { l.get().Debugf(format, args...) }
+func (l *atomicLogger) Info(args ...interface{})                  { l.get().Info(args...) }
+func (l *atomicLogger) Infof(format string, args ...interface{})  { l.get().Infof(format, args...) }
+func (l *atomicLogger) Warn(args ...interface{})                  { l.get().Warn(args...) }
+func (l *atomicLogger) Warnf(format string, args ...interface{})  { l.get().Warnf(format, args...) }
+func (l *atomicLogger) Error(args ...interface{})                 { l.get().Error(args...) }
+func (l *atomicLogger) Errorf(format string, args ...interface{}) { l.get().Errorf(format, args...) }
+
+// log is the package-wide logger.  It defaults to a thin wrapper around
+// gopkg.in/op/go-logging.v1; library users should call SetLogger before
+// doing anything else if they want go-jira's log output routed
+// elsewhere.  It is safe to call SetLogger concurrently with logging
+// calls from other goroutines.
+var log = newAtomicLogger()
+
+func newAtomicLogger() *atomicLogger {
+	l := &atomicLogger{}
+	l.v.Store(loggerBox{l: newDefaultLogger()})
+	return l
+}
+
+// SetLogger replaces go-jira's package-wide logger, on the Jira client
+// and everywhere else go-jira logs from.  Library users should call
+// this instead of relying on the default, which writes to stderr.
+func SetLogger(l Logger) {
+	log.v.Store(loggerBox{l: l})
+}
+
+type opLogger struct {
+	backend *golog.Logger
+}
+
+func newDefaultLogger() Logger {
+	return &opLogger{backend: golog.MustGetLogger("jira")}
+}
+
+func (l *opLogger) Debug(args ...interface{})                 { l.backend.Debug(fmt.Sprint(args...)) }
+func (l *opLogger) Debugf(format string, args ...interface{}) { l.backend.Debugf(format, args...) }
+func (l *opLogger) Info(args ...interface{})                  { l.backend.Info(fmt.Sprint(args...)) }
+func (l *opLogger) Infof(format string, args ...interface{})  { l.backend.Infof(format, args...) }
+func (l *opLogger) Warn(args ...interface{})                  { l.backend.Warning(fmt.Sprint(args...)) }
+func (l *opLogger) Warnf(format string, args ...interface{})  { l.backend.Warningf(format, args...) }
+func (l *opLogger) Error(args ...interface{})                 { l.backend.Error(fmt.Sprint(args...)) }
+func (l *opLogger) Errorf(format string, args ...interface{}) { l.backend.Errorf(format, args...) }
+
+// JSONLogger is a Logger that writes one JSON object per log line,
+// for operators who want to pipe go-jira's logging into a structured
+// log pipeline instead of the human readable default.
+type JSONLogger struct {
+	out io.Writer
+}
+
+// NewJSONLogger returns a Logger that writes JSON lines to out.
+func NewJSONLogger(out io.Writer) *JSONLogger {
+	return &JSONLogger{out: out}
+}
+
+type jsonLogLine struct {
+	Time  time.Time `json:"time"`
+	Level string    `json:"level"`
+	Msg   string    `json:"msg"`
+}
+
+func (l *JSONLogger) write(level, msg string) {
+	line, err := json.Marshal(jsonLogLine{Time: time.Now(), Level: level, Msg: msg})
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(l.out, string(line))
+}
+
+func (l *JSONLogger) Debug(args ...interface{})         { l.write("debug", fmt.Sprint(args...)) }
+func (l *JSONLogger) Debugf(f string, a ...interface{}) { l.write("debug", fmt.Sprintf(f, a...)) }
+func (l *JSONLogger) Info(args ...interface{})          { l.write("info", fmt.Sprint(args...)) }
+func (l *JSONLogger) Infof(f string, a ...interface{})  { l.write("info", fmt.Sprintf(f, a...)) }
+func (l *JSONLogger) Warn(args ...interface{})          { l.write("warn", fmt.Sprint(args...)) }
+func (l *JSONLogger) Warnf(f string, a ...interface{})  { l.write("warn", fmt.Sprintf(f, a...)) }
+func (l *JSONLogger) Error(args ...interface{})         { l.write("error", fmt.Sprint(args...)) }
+func (l *JSONLogger) Errorf(f string, a ...interface{}) { l.write("error", fmt.Sprintf(f, a...)) }
@@ -0,0 +1,39 @@
+package jira
+
+import (
+	"sync"
+	"testing"
+)
+
+type nullLogger struct{}
+
+func (nullLogger) Debug(args ...interface{})                 {}
+func (nullLogger) Debugf(format string, args ...interface{}) {}
+func (nullLogger) Info(args ...interface{})                  {}
+func (nullLogger) Infof(format string, args ...interface{})  {}
+func (nullLogger) Warn(args ...interface{})                  {}
+func (nullLogger) Warnf(format string, args ...interface{})  {}
+func (nullLogger) Error(args ...interface{})                 {}
+func (nullLogger) Errorf(format string, args ...interface{}) {}
+
+// TestSetLoggerConcurrent exercises SetLogger and logging calls from
+// many goroutines at once; run with -race to catch regressions of the
+// data race where the package-wide log variable was swapped and read
+// without synchronization.
+func TestSetLoggerConcurrent(t *testing.T) {
+	defer SetLogger(newDefaultLogger())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetLogger(nullLogger{})
+		}()
+		go func() {
+			defer wg.Done()
+			log.Errorf("test message")
+		}()
+	}
+	wg.Wait()
+}
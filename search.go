@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// HTTPDoer is satisfied by *http.Client and by the transport embedded in
+// go-jira's own client.  IterateIssues takes one in rather than
+// constructing its own http.Client, so callers run it over whatever
+// authenticated transport they already have instead of a second,
+// unauthenticated one.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// defaultMaxResults is the page size IterateIssues requests from
+// /search; callers cannot change it.
+const defaultMaxResults = 50
+
+// IterateIssues runs jql against endpoint's /search and streams each
+// matching issue to fn as it is decoded, paging under the hood with
+// startAt/maxResults so that large result sets (thousands of issues,
+// possibly with expand=changelog) never need to be fully buffered in
+// memory.  client is used as-is, so its authentication (session
+// cookies, basic auth, whatever the caller's Jira client already sets
+// up) carries over unchanged.  Iteration stops as soon as fn returns an
+// error, which is then returned to the caller.
+func IterateIssues(client HTTPDoer, endpoint *url.URL, jql string, fn func(issue map[string]interface{}) error) error {
+	startAt := 0
+	for {
+		resp, err := searchIssues(client, endpoint, jql, startAt, defaultMaxResults)
+		if err != nil {
+			return err
+		}
+
+		page, err := decodeSearchPage(resp)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range page.Issues {
+			if err := fn(issue); err != nil {
+				return err
+			}
+		}
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			return nil
+		}
+	}
+}
+
+func searchIssues(client HTTPDoer, endpoint *url.URL, jql string, startAt, maxResults int) (*http.Response, error) {
+	search := *endpoint
+	search.Path = search.Path + "/rest/api/2/search"
+
+	q := url.Values{}
+	q.Set("jql", jql)
+	q.Set("startAt", fmt.Sprintf("%d", startAt))
+	q.Set("maxResults", fmt.Sprintf("%d", maxResults))
+	q.Set("expand", "changelog")
+	search.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", search.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// searchPage is the shape of a single page of a /search response.
+type searchPage struct {
+	Total  int                      `json:"total"`
+	Issues []map[string]interface{} `json:"issues"`
+}
+
+// decodeSearchPage streams a /search response body through
+// json.Decoder rather than buffering it whole, the same way jsonDecode
+// does for single-object responses.  Non-2xx responses (expired
+// session, permission error, server error, ...) are reported as errors
+// instead of being decoded as if they were a (possibly empty) result
+// page.
+func decodeSearchPage(resp *http.Response) (searchPage, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return searchPage{}, fmt.Errorf("search: unexpected response from %s: %s", resp.Request.URL, decodeErrorBody(resp.Body))
+	}
+
+	var page searchPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return searchPage{}, err
+	}
+	return page, nil
+}
+
+func decodeErrorBody(body io.Reader) string {
+	var errs struct {
+		ErrorMessages []string `json:"errorMessages"`
+	}
+	if err := json.NewDecoder(body).Decode(&errs); err != nil || len(errs.ErrorMessages) == 0 {
+		return "request failed"
+	}
+	return fmt.Sprintf("%v", errs.ErrorMessages)
+}
@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+var errStop = errors.New("stop iteration")
+
+func TestIterateIssuesMultiPage(t *testing.T) {
+	pages := [][]map[string]interface{}{
+		{{"key": "A-1"}, {"key": "A-2"}},
+		{{"key": "A-3"}},
+	}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startAt := r.URL.Query().Get("startAt")
+		var issues []map[string]interface{}
+		switch startAt {
+		case "0":
+			issues = pages[0]
+		case "2":
+			issues = pages[1]
+		default:
+			t.Fatalf("unexpected startAt: %s", startAt)
+		}
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"total": 3, "issues": issues})
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	var got []string
+	err := IterateIssues(http.DefaultClient, u, "project=FOO", func(issue map[string]interface{}) error {
+		got = append(got, issue["key"].(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("IterateIssues: unexpected error: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("made %d requests, want 2", calls)
+	}
+	want := []string{"A-1", "A-2", "A-3"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIterateIssuesZeroResults(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"total": 0, "issues": []map[string]interface{}{}})
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	var got []string
+	err := IterateIssues(http.DefaultClient, u, "project=FOO", func(issue map[string]interface{}) error {
+		got = append(got, issue["key"].(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("IterateIssues: unexpected error: %s", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %v, want none", got)
+	}
+	if calls != 1 {
+		t.Errorf("made %d requests, want 1", calls)
+	}
+}
+
+func TestIterateIssuesNon2xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errorMessages": []string{"permission denied"}})
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	err := IterateIssues(http.DefaultClient, u, "project=FOO", func(issue map[string]interface{}) error {
+		t.Fatalf("fn should not be called, got issue %v", issue)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("IterateIssues: expected error, got none")
+	}
+}
+
+func TestIterateIssuesStopsOnCallbackError(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total":  2,
+			"issues": []map[string]interface{}{{"key": "A-1"}, {"key": "A-2"}},
+		})
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	wantErr := errStop
+	err := IterateIssues(http.DefaultClient, u, "project=FOO", func(issue map[string]interface{}) error {
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("made %d requests, want 1", calls)
+	}
+}
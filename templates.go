@@ -0,0 +1,182 @@
+package jira
+
+import (
+	"fmt"
+	"gopkg.in/coryb/yaml.v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// frontMatterDelim marks the start and end of the YAML header that may
+// precede a template body, following the convention used by many static
+// site / templating tools of embedding metadata directly in the Markdown
+// file.
+const frontMatterDelim = "---"
+
+// Template describes a named template file found under a templates
+// directory, along with the variables parsed out of its YAML front
+// matter.  It is returned by ListTemplates for tab completion and for
+// the `jira templates` listing.
+type Template struct {
+	Name string
+	Path string
+	Meta map[string]interface{}
+}
+
+// splitFrontMatter looks for a `---`-delimited YAML header at the start
+// of content.  If one is found it is parsed and returned along with the
+// remaining body; if content has no front matter, meta is nil and body
+// is the content unchanged.
+func splitFrontMatter(content string) (meta map[string]interface{}, body string, err error) {
+	if !strings.HasPrefix(content, frontMatterDelim+"\n") {
+		return nil, content, nil
+	}
+
+	rest := content[len(frontMatterDelim+"\n"):]
+	end := strings.Index(rest, "\n"+frontMatterDelim+"\n")
+	if end == -1 {
+		if strings.HasSuffix(rest, "\n"+frontMatterDelim) {
+			end = len(rest) - len("\n"+frontMatterDelim)
+			body = ""
+		} else {
+			return nil, content, nil
+		}
+	} else {
+		body = rest[end+len("\n"+frontMatterDelim+"\n"):]
+	}
+
+	header := rest[:end]
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(header), &raw); err != nil {
+		return nil, "", err
+	}
+
+	// yamlFixup turns the map[interface{}]interface{} that yaml.v2
+	// produces for nested mappings into map[string]interface{}, the same
+	// way fromYaml does for template data, so Template.Meta stays
+	// JSON-encodable.
+	fixed, err := yamlFixup(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	if fixed == nil {
+		return nil, body, nil
+	}
+	meta, ok := fixed.(map[string]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("template front matter must be a YAML mapping, got %T", fixed)
+	}
+	return meta, body, nil
+}
+
+// mergeFrontMatter overlays data on top of the variables parsed from a
+// template's front matter, so that explicit data (eg: command line
+// overrides) always wins over the metadata defaults stored in the
+// template file.  data may be a map[string]interface{}, or a struct (or
+// pointer to struct) as text/template has always accepted directly, so
+// that a caller's existing data type keeps working once its template
+// gains a front matter header.
+func mergeFrontMatter(meta map[string]interface{}, data interface{}) (interface{}, error) {
+	if len(meta) == 0 {
+		return data, nil
+	}
+
+	merged := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		merged[k] = v
+	}
+
+	fields, err := structFields(data)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// structFields returns data's exported fields keyed by field name, so
+// they can be overlaid into the map mergeFrontMatter builds out of
+// front matter.  data may be nil, a map[string]interface{}, a struct or
+// a pointer to one; anything else (a slice, a bare string, ...) can't be
+// merged with front matter and is reported as an error rather than
+// silently dropped.
+func structFields(data interface{}) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if m, ok := data.(map[string]interface{}); ok {
+		return m, nil
+	}
+
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("template front matter requires map[string]interface{} or struct data, got %T", data)
+	}
+
+	t := v.Type()
+	fields := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fields[field.Name] = v.Field(i).Interface()
+	}
+	return fields, nil
+}
+
+// TemplatesDir finds the `~/.jira.d/templates` directory closest to the
+// current working directory, reusing the same parent-path search used
+// for `.jira.d/config.yml`.
+func TemplatesDir() (string, error) {
+	return FindClosestParentPath(filepath.Join(".jira.d", "templates"))
+}
+
+// ListTemplates walks the templates directory and returns the name and
+// parsed front matter of every template found there, so that callers
+// (tab completion, `jira templates`) don't have to parse the files
+// themselves.
+func ListTemplates() ([]Template, error) {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		meta, _, err := splitFrontMatter(string(content))
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		templates = append(templates, Template{
+			Name: name,
+			Path: path,
+			Meta: meta,
+		})
+	}
+	return templates, nil
+}
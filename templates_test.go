@@ -0,0 +1,194 @@
+package jira
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantMeta map[string]interface{}
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "no front matter",
+			content:  "Summary: {{.Summary}}\n",
+			wantMeta: nil,
+			wantBody: "Summary: {{.Summary}}\n",
+		},
+		{
+			name:     "empty front matter",
+			content:  "---\n\n---\nSummary: {{.Summary}}\n",
+			wantMeta: nil,
+			wantBody: "Summary: {{.Summary}}\n",
+		},
+		{
+			name:     "unterminated front matter delimiter is treated as plain content",
+			content:  "---\n---\nSummary: {{.Summary}}\n",
+			wantMeta: nil,
+			wantBody: "---\n---\nSummary: {{.Summary}}\n",
+		},
+		{
+			name:    "simple front matter",
+			content: "---\nproject: FOO\nissuetype: Bug\n---\nSummary: {{.Summary}}\n",
+			wantMeta: map[string]interface{}{
+				"project":   "FOO",
+				"issuetype": "Bug",
+			},
+			wantBody: "Summary: {{.Summary}}\n",
+		},
+		{
+			name:    "nested mapping is fixed up to map[string]interface{}",
+			content: "---\nproject:\n  key: FOO\n  lead: bob\n---\nbody\n",
+			wantMeta: map[string]interface{}{
+				"project": map[string]interface{}{
+					"key":  "FOO",
+					"lead": "bob",
+				},
+			},
+			wantBody: "body\n",
+		},
+		{
+			name:    "non-mapping front matter is an error",
+			content: "---\n- one\n- two\n---\nbody\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, body, err := splitFrontMatter(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitFrontMatter(%q): expected error, got none", tt.content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitFrontMatter(%q): unexpected error: %s", tt.content, err)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if !mapsEqual(meta, tt.wantMeta) {
+				t.Errorf("meta = %#v, want %#v", meta, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestMergeFrontMatter(t *testing.T) {
+	type issueData struct {
+		Summary string
+	}
+
+	tests := []struct {
+		name    string
+		meta    map[string]interface{}
+		data    interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "no front matter returns data unchanged",
+			meta: nil,
+			data: issueData{Summary: "hello"},
+			want: issueData{Summary: "hello"},
+		},
+		{
+			name: "map data is overlaid on meta",
+			meta: map[string]interface{}{"project": "FOO"},
+			data: map[string]interface{}{"project": "BAR", "Summary": "hi"},
+			want: map[string]interface{}{"project": "BAR", "Summary": "hi"},
+		},
+		{
+			name: "struct data is merged field by field, not discarded",
+			meta: map[string]interface{}{"project": "FOO"},
+			data: issueData{Summary: "hello"},
+			want: map[string]interface{}{"project": "FOO", "Summary": "hello"},
+		},
+		{
+			name: "pointer to struct is dereferenced",
+			meta: map[string]interface{}{"project": "FOO"},
+			data: &issueData{Summary: "hello"},
+			want: map[string]interface{}{"project": "FOO", "Summary": "hello"},
+		},
+		{
+			name: "nil data with front matter keeps meta",
+			meta: map[string]interface{}{"project": "FOO"},
+			data: nil,
+			want: map[string]interface{}{"project": "FOO"},
+		},
+		{
+			name:    "non-struct, non-map data with front matter is an error",
+			meta:    map[string]interface{}{"project": "FOO"},
+			data:    []string{"a", "b"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeFrontMatter(tt.meta, tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("mergeFrontMatter(%#v, %#v): expected error, got none", tt.meta, tt.data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeFrontMatter(%#v, %#v): unexpected error: %s", tt.meta, tt.data, err)
+			}
+			if !valuesEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRunTemplateFrontMatterWithStructData is a regression test: a
+// struct passed to RunTemplate must still be visible to the template
+// once the template gains a front matter header, not silently replaced
+// by the front matter alone.
+func TestRunTemplateFrontMatterWithStructData(t *testing.T) {
+	type issueData struct {
+		Summary string
+	}
+
+	var out bytes.Buffer
+	err := RunTemplate("---\nproject: FOO\n---\nSummary: {{.Summary}}\n", issueData{Summary: "hello"}, &out)
+	if err != nil {
+		t.Fatalf("RunTemplate: unexpected error: %s", err)
+	}
+	if want := "Summary: hello\n"; out.String() != want {
+		t.Errorf("RunTemplate output = %q, want %q", out.String(), want)
+	}
+}
+
+func mapsEqual(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		bv, ok := b[k]
+		if !ok || !valuesEqual(v, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+func valuesEqual(a, b interface{}) bool {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if aok || bok {
+		if aok != bok {
+			return false
+		}
+		return mapsEqual(am, bm)
+	}
+	return a == b
+}
@@ -3,17 +3,19 @@ package jira
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/mgutz/ansi"
 	"gopkg.in/coryb/yaml.v2"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 )
@@ -68,15 +70,14 @@ func FindClosestParentPath(fileName string) (string, error) {
 	return "", fmt.Errorf("%s not found in parent directory hierarchy", fileName)
 }
 
-func readFile(file string) string {
-	var bytes []byte
-	var err error
+func readFile(file string) (string, error) {
 	log.Debugf("readFile: reading %q", file)
-	if bytes, err = ioutil.ReadFile(file); err != nil {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
 		log.Errorf("Failed to read file %s: %s", file, err)
-		os.Exit(1)
+		return "", err
 	}
-	return string(bytes)
+	return string(bytes), nil
 }
 
 func copyFile(src, dst string) (err error) {
@@ -125,16 +126,224 @@ func dateFormat(format string, content string) (string, error) {
 // RunTemplate will run the given templateContent as a golang text/template
 // and pass the provided data to the template execution.  It will write
 // the output to the provided "out" writer.
+//
+// If templateContent begins with a `---`-delimited YAML front matter
+// header, it is parsed out and merged underneath data before the
+// remaining body is rendered, so that templates can carry their own
+// default variables (project, issuetype, labels, ...) alongside the
+// body text.
 func RunTemplate(templateContent string, data interface{}, out io.Writer) error {
-	return runTemplate(templateContent, data, out)
+	meta, body, err := splitFrontMatter(templateContent)
+	if err != nil {
+		log.Errorf("Failed to parse template front matter: %s", err)
+		return err
+	}
+	merged, err := mergeFrontMatter(meta, data)
+	if err != nil {
+		log.Errorf("Failed to merge template front matter: %s", err)
+		return err
+	}
+	return runTemplate(body, merged, out)
 }
 
-func runTemplate(templateContent string, data interface{}, out io.Writer) error {
-	if out == nil {
-		out = os.Stdout
+// templateFuncsMu guards templateFuncs and templateFuncBlacklist, since
+// RegisterTemplateFunc/DisableTemplateFunc/EnableTemplateFunc are meant
+// to be usable from a concurrent service (eg: toggling the blacklist
+// per incoming template to sandbox untrusted sources) while runTemplate
+// is reading the same maps on every call.
+var templateFuncsMu sync.RWMutex
+
+// templateFuncs holds the built-in template helpers plus anything registered
+// via RegisterTemplateFunc.  A copy of it, minus any names disabled via
+// DisableTemplateFunc, is handed to text/template.Funcs for every call to
+// RunTemplate.
+var templateFuncs = map[string]interface{}{}
+
+// templateFuncBlacklist holds the names of functions that have been
+// disabled via DisableTemplateFunc.  Disabled functions are removed from
+// the map handed to text/template, even if they are builtins or were
+// registered later, which lets callers sandbox templates that come from
+// untrusted/remote sources.
+var templateFuncBlacklist = map[string]bool{}
+
+// RegisterTemplateFunc adds (or overrides) a function that will be made
+// available to every template executed by RunTemplate.  It is meant for
+// users embedding go-jira as a library who want custom helpers in their
+// issue/list templates without recompiling go-jira itself.
+func RegisterTemplateFunc(name string, fn interface{}) {
+	templateFuncsMu.Lock()
+	defer templateFuncsMu.Unlock()
+	templateFuncs[name] = fn
+}
+
+// DisableTemplateFunc removes a function, builtin or user registered, from
+// the set made available to templates.  Use this to sandbox templates
+// sourced from somewhere you don't fully trust.
+func DisableTemplateFunc(name string) {
+	templateFuncsMu.Lock()
+	defer templateFuncsMu.Unlock()
+	templateFuncBlacklist[name] = true
+}
+
+// EnableTemplateFunc reverses a previous call to DisableTemplateFunc.
+func EnableTemplateFunc(name string) {
+	templateFuncsMu.Lock()
+	defer templateFuncsMu.Unlock()
+	delete(templateFuncBlacklist, name)
+}
+
+func init() {
+	templateFuncsMu.Lock()
+	defer templateFuncsMu.Unlock()
+	for name, fn := range builtinTemplateFuncs() {
+		templateFuncs[name] = fn
 	}
+}
 
-	funcs := map[string]interface{}{
+// builtinTemplateFuncs returns the batteries-included set of template
+// helpers shipped with go-jira, in the spirit of the helper sets found in
+// other Go templating tools: string manipulation, collection helpers,
+// basic math, encoding conversions, date arithmetic and simple control
+// flow.
+func builtinTemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"title": strings.Title,
+		"trim":  strings.TrimSpace,
+		"replace": func(old, new, content string) string {
+			return strings.Replace(content, old, new, -1)
+		},
+		"regexReplace": func(pattern, repl, content string) (string, error) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			return re.ReplaceAllString(content, repl), nil
+		},
+		"first": func(list []interface{}) interface{} {
+			if len(list) == 0 {
+				return nil
+			}
+			return list[0]
+		},
+		"last": func(list []interface{}) interface{} {
+			if len(list) == 0 {
+				return nil
+			}
+			return list[len(list)-1]
+		},
+		"slice": func(list []interface{}, indices ...int) []interface{} {
+			start := 0
+			end := len(list)
+			if len(indices) > 0 {
+				start = indices[0]
+			}
+			if len(indices) > 1 {
+				end = indices[1]
+			}
+			if start < 0 || end > len(list) || start > end {
+				return []interface{}{}
+			}
+			return list[start:end]
+		},
+		"dict": func(pairs ...interface{}) (map[string]interface{}, error) {
+			if len(pairs)%2 != 0 {
+				return nil, fmt.Errorf("dict requires an even number of arguments")
+			}
+			d := make(map[string]interface{}, len(pairs)/2)
+			for i := 0; i < len(pairs); i += 2 {
+				key, ok := pairs[i].(string)
+				if !ok {
+					return nil, fmt.Errorf("dict keys must be strings, got %T", pairs[i])
+				}
+				d[key] = pairs[i+1]
+			}
+			return d, nil
+		},
+		"list": func(items ...interface{}) []interface{} {
+			return items
+		},
+		"has": func(item interface{}, list []interface{}) bool {
+			for _, i := range list {
+				if i == item {
+					return true
+				}
+			}
+			return false
+		},
+		"add": func(a, b int) int { return a + b },
+		"sub": func(a, b int) int { return a - b },
+		"mul": func(a, b int) int { return a * b },
+		"div": func(a, b int) (int, error) {
+			if b == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return a / b, nil
+		},
+		"mod": func(a, b int) (int, error) {
+			if b == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return a % b, nil
+		},
+		"toYaml": func(content interface{}) (string, error) {
+			out, err := yaml.Marshal(content)
+			if err != nil {
+				return "", err
+			}
+			return string(out), nil
+		},
+		"fromYaml": func(content string) (interface{}, error) {
+			var data interface{}
+			if err := yaml.Unmarshal([]byte(content), &data); err != nil {
+				return nil, err
+			}
+			return yamlFixup(data)
+		},
+		"fromJson": func(content string) (interface{}, error) {
+			var data interface{}
+			err := json.Unmarshal([]byte(content), &data)
+			return data, err
+		},
+		"b64enc": func(content string) string {
+			return base64.StdEncoding.EncodeToString([]byte(content))
+		},
+		"b64dec": func(content string) (string, error) {
+			out, err := base64.StdEncoding.DecodeString(content)
+			return string(out), err
+		},
+		"now": func() time.Time {
+			return time.Now()
+		},
+		"dateAdd": func(duration string, t time.Time) (time.Time, error) {
+			d, err := time.ParseDuration(duration)
+			if err != nil {
+				return t, err
+			}
+			return t.Add(d), nil
+		},
+		"parseDate": func(layout, content string) (time.Time, error) {
+			return time.Parse(layout, content)
+		},
+		"default": func(def, content interface{}) interface{} {
+			if content == nil || content == "" {
+				return def
+			}
+			return content
+		},
+		"ternary": func(truthy, falsy interface{}, cond bool) interface{} {
+			if cond {
+				return truthy
+			}
+			return falsy
+		},
+		"required": func(msg string, content interface{}) (interface{}, error) {
+			if content == nil || content == "" {
+				return nil, fmt.Errorf("%s", msg)
+			}
+			return content, nil
+		},
 		"toJson": func(content interface{}) (string, error) {
 			bytes, err := json.MarshalIndent(content, "", "    ")
 			if err != nil {
@@ -210,6 +419,23 @@ func runTemplate(templateContent string, data interface{}, out io.Writer) error
 			return dateFormat(format, content)
 		},
 	}
+}
+
+func runTemplate(templateContent string, data interface{}, out io.Writer) error {
+	if out == nil {
+		out = os.Stdout
+	}
+
+	templateFuncsMu.RLock()
+	funcs := make(map[string]interface{}, len(templateFuncs))
+	for name, fn := range templateFuncs {
+		if templateFuncBlacklist[name] {
+			continue
+		}
+		funcs[name] = fn
+	}
+	templateFuncsMu.RUnlock()
+
 	tmpl, err := template.New("template").Funcs(funcs).Parse(templateContent)
 	if err != nil {
 		log.Errorf("Failed to parse template: %s", err)
@@ -239,12 +465,10 @@ func responseToJSON(resp *http.Response, err error) (interface{}, error) {
 	return data, nil
 }
 
-func jsonDecode(io io.Reader) interface{} {
-	content, err := ioutil.ReadAll(io)
+func jsonDecode(r io.Reader) interface{} {
 	var data interface{}
-	err = json.Unmarshal(content, &data)
-	if err != nil {
-		log.Errorf("JSON Parse Error: %s from %s", err, content)
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		log.Errorf("JSON Parse Error: %s", err)
 	}
 	return data
 }
@@ -261,30 +485,39 @@ func jsonEncode(data interface{}) (string, error) {
 	return buffer.String(), nil
 }
 
-func jsonWrite(file string, data interface{}) {
+func jsonWrite(file string, data interface{}) error {
 	fh, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
-	defer fh.Close()
 	if err != nil {
 		log.Errorf("Failed to open %s: %s", file, err)
-		os.Exit(1)
+		return err
+	}
+	defer fh.Close()
+
+	if err := json.NewEncoder(fh).Encode(data); err != nil {
+		log.Errorf("Failed to encode %s: %s", file, err)
+		return err
 	}
-	enc := json.NewEncoder(fh)
-	enc.Encode(data)
+	return nil
 }
 
-func yamlWrite(file string, data interface{}) {
+func yamlWrite(file string, data interface{}) error {
 	fh, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
-	defer fh.Close()
 	if err != nil {
 		log.Errorf("Failed to open %s: %s", file, err)
-		os.Exit(1)
+		return err
 	}
-	if out, err := yaml.Marshal(data); err != nil {
+	defer fh.Close()
+
+	out, err := yaml.Marshal(data)
+	if err != nil {
 		log.Errorf("Failed to marshal yaml %v: %s", data, err)
-		os.Exit(1)
-	} else {
-		fh.Write(out)
+		return err
 	}
+	if _, err := fh.Write(out); err != nil {
+		log.Errorf("Failed to write %s: %s", file, err)
+		return err
+	}
+	return nil
 }
 
 func promptYN(prompt string, yes bool) bool {
@@ -0,0 +1,126 @@
+package jira
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func builtinFunc(t *testing.T, name string) interface{} {
+	t.Helper()
+	fn, ok := builtinTemplateFuncs()[name]
+	if !ok {
+		t.Fatalf("no builtin template func named %q", name)
+	}
+	return fn
+}
+
+func TestBuiltinTemplateFuncsDict(t *testing.T) {
+	dict := builtinFunc(t, "dict").(func(...interface{}) (map[string]interface{}, error))
+
+	if _, err := dict("a", 1, "b"); err == nil {
+		t.Error("dict with an odd number of arguments: expected error, got none")
+	}
+	if _, err := dict(1, "a"); err == nil {
+		t.Error("dict with a non-string key: expected error, got none")
+	}
+	got, err := dict("a", 1, "b", 2)
+	if err != nil {
+		t.Fatalf("dict: unexpected error: %s", err)
+	}
+	want := map[string]interface{}{"a": 1, "b": 2}
+	if len(got) != len(want) || got["a"] != want["a"] || got["b"] != want["b"] {
+		t.Errorf("dict(\"a\", 1, \"b\", 2) = %#v, want %#v", got, want)
+	}
+}
+
+func TestBuiltinTemplateFuncsDiv(t *testing.T) {
+	div := builtinFunc(t, "div").(func(int, int) (int, error))
+
+	if _, err := div(4, 0); err == nil {
+		t.Error("div by zero: expected error, got none")
+	}
+	got, err := div(7, 2)
+	if err != nil {
+		t.Fatalf("div: unexpected error: %s", err)
+	}
+	if got != 3 {
+		t.Errorf("div(7, 2) = %d, want 3", got)
+	}
+}
+
+func TestBuiltinTemplateFuncsMod(t *testing.T) {
+	mod := builtinFunc(t, "mod").(func(int, int) (int, error))
+
+	if _, err := mod(4, 0); err == nil {
+		t.Error("mod by zero: expected error, got none")
+	}
+	got, err := mod(7, 2)
+	if err != nil {
+		t.Fatalf("mod: unexpected error: %s", err)
+	}
+	if got != 1 {
+		t.Errorf("mod(7, 2) = %d, want 1", got)
+	}
+}
+
+func TestBuiltinTemplateFuncsSlice(t *testing.T) {
+	slice := builtinFunc(t, "slice").(func([]interface{}, ...int) []interface{})
+
+	list := []interface{}{1, 2, 3, 4}
+
+	if got := slice(list); len(got) != len(list) {
+		t.Errorf("slice(list) = %v, want %v", got, list)
+	}
+	if got := slice(list, 1, 3); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("slice(list, 1, 3) = %v, want [2 3]", got)
+	}
+	if got := slice(list, -1, 3); len(got) != 0 {
+		t.Errorf("slice(list, -1, 3) with negative start: got %v, want []", got)
+	}
+	if got := slice(list, 0, 10); len(got) != 0 {
+		t.Errorf("slice(list, 0, 10) out of range: got %v, want []", got)
+	}
+	if got := slice(list, 3, 1); len(got) != 0 {
+		t.Errorf("slice(list, 3, 1) with start > end: got %v, want []", got)
+	}
+}
+
+func TestBuiltinTemplateFuncsRequired(t *testing.T) {
+	required := builtinFunc(t, "required").(func(string, interface{}) (interface{}, error))
+
+	if _, err := required("must be set", nil); err == nil {
+		t.Error("required(nil): expected error, got none")
+	}
+	if _, err := required("must be set", ""); err == nil {
+		t.Error(`required(""): expected error, got none`)
+	}
+	got, err := required("must be set", "hello")
+	if err != nil {
+		t.Fatalf("required: unexpected error: %s", err)
+	}
+	if got != "hello" {
+		t.Errorf("required(\"hello\") = %v, want \"hello\"", got)
+	}
+}
+
+// TestRegisterTemplateFuncConcurrent exercises RegisterTemplateFunc and
+// RunTemplate from many goroutines at once; run with -race to catch
+// regressions of the data race where templateFuncs/templateFuncBlacklist
+// were read and written without synchronization.
+func TestRegisterTemplateFuncConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterTemplateFunc("noop", func() string { return "noop" })
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			var out bytes.Buffer
+			RunTemplate("{{upper \"hi\"}}", nil, &out)
+		}(i)
+	}
+	wg.Wait()
+}
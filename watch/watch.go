@@ -0,0 +1,269 @@
+// Package watch monitors a go-jira template file and a JQL query,
+// re-running the query and reporting what changed in its result
+// whenever either one changes: the template is watched locally via
+// fsnotify, the query is re-run on Jira on a configurable polling
+// interval.  Only issues that were added, transitioned or removed are
+// printed; a template change alone does not re-print unchanged issues.
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	jira "github.com/mlbright/go-jira"
+)
+
+// defaultDebounce coalesces bursts of filesystem events (editors that
+// write a file more than once per save) into a single re-render.
+const defaultDebounce = 100 * time.Millisecond
+
+// defaultPollInterval is used when Config.PollInterval is left at its
+// zero value, since time.NewTicker panics on a non-positive interval.
+const defaultPollInterval = 30 * time.Second
+
+// SearchFunc runs a JQL query and returns the matching issues, keyed by
+// the usual Jira `fields`/`key` shaped map.  It keeps Watcher
+// independent of any particular Jira client implementation.
+type SearchFunc func(jql string) ([]map[string]interface{}, error)
+
+// Config describes what to watch and how to react when it changes.
+type Config struct {
+	// TemplatePath is the template file to watch for local changes and,
+	// if set, to render each changed issue through.  Resolved relative
+	// to the current working directory with FindClosestParentPath.
+	TemplatePath string
+	// Query is the JQL query to re-run whenever the template changes or
+	// the poll interval elapses.
+	Query string
+	// PollInterval controls how often Query is re-run against Jira.
+	// Defaults to 30s if unset.
+	PollInterval time.Duration
+	// Debounce defaults to 100ms if unset.
+	Debounce time.Duration
+	// Signal, if set, is run as a shell command whenever the query
+	// result changes, eg: to trigger a desktop notification.
+	Signal string
+}
+
+// Watcher monitors a template file and a JQL query, re-running the
+// query and rendering the issues whose result changed whenever either
+// one changes.
+type Watcher struct {
+	cfg    Config
+	search SearchFunc
+	out    io.Writer
+
+	template string
+	prev     map[string]map[string]interface{}
+}
+
+// New creates a Watcher for the given config.  search is used to
+// re-run cfg.Query; out receives the rendered diff.
+func New(cfg Config, search SearchFunc, out io.Writer) (*Watcher, error) {
+	if cfg.Debounce == 0 {
+		cfg.Debounce = defaultDebounce
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.TemplatePath != "" {
+		path, err := jira.FindClosestParentPath(cfg.TemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TemplatePath = path
+	}
+	return &Watcher{
+		cfg:    cfg,
+		search: search,
+		out:    out,
+		prev:   map[string]map[string]interface{}{},
+	}, nil
+}
+
+// Run blocks, watching the template file and polling the query until
+// stop is closed.
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer notify.Close()
+
+	if w.cfg.TemplatePath != "" {
+		if err := notify.Add(w.cfg.TemplatePath); err != nil {
+			return err
+		}
+		if err := w.loadTemplate(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.refresh(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	changed := make(chan struct{}, 1)
+	var debounce *time.Timer
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-notify.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(w.cfg.Debounce, func() {
+					changed <- struct{}{}
+				})
+			} else {
+				debounce.Reset(w.cfg.Debounce)
+			}
+		case err, ok := <-notify.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-changed:
+			if err := w.loadTemplate(); err != nil {
+				return err
+			}
+			if err := w.refresh(); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := w.refresh(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *Watcher) loadTemplate() error {
+	content, err := os.ReadFile(w.cfg.TemplatePath)
+	if err != nil {
+		return err
+	}
+	w.template = string(content)
+	return nil
+}
+
+// refresh re-runs the query, diffs it against the previous result and
+// prints what changed, signalling the configured command if anything
+// did.
+func (w *Watcher) refresh() error {
+	issues, err := w.search(w.cfg.Query)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]map[string]interface{}, len(issues))
+	for _, issue := range issues {
+		key, _ := issue["key"].(string)
+		current[key] = issue
+	}
+
+	diff := w.diff(current)
+	w.prev = current
+	if diff.empty() {
+		return nil
+	}
+
+	if err := w.print(diff, current); err != nil {
+		return err
+	}
+
+	if w.cfg.Signal != "" {
+		return w.notify()
+	}
+	return nil
+}
+
+type resultDiff struct {
+	added        []string
+	removed      []string
+	transitioned []string
+}
+
+func (d resultDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.transitioned) == 0
+}
+
+func (w *Watcher) diff(current map[string]map[string]interface{}) resultDiff {
+	var d resultDiff
+	for key, issue := range current {
+		old, existed := w.prev[key]
+		if !existed {
+			d.added = append(d.added, key)
+			continue
+		}
+		if statusOf(old) != statusOf(issue) {
+			d.transitioned = append(d.transitioned, key)
+		}
+	}
+	for key := range w.prev {
+		if _, ok := current[key]; !ok {
+			d.removed = append(d.removed, key)
+		}
+	}
+	return d
+}
+
+func (w *Watcher) print(diff resultDiff, current map[string]map[string]interface{}) error {
+	for _, key := range diff.added {
+		if err := w.renderIssue("+", key, current[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range diff.transitioned {
+		if err := w.renderIssue("~", key, current[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range diff.removed {
+		fmt.Fprintf(w.out, "- %s\n", key)
+	}
+	return nil
+}
+
+func (w *Watcher) renderIssue(marker, key string, issue map[string]interface{}) error {
+	if w.template == "" {
+		fmt.Fprintf(w.out, "%s %s\n", marker, key)
+		return nil
+	}
+	fmt.Fprintf(w.out, "%s ", marker)
+	return jira.RunTemplate(w.template, issue, w.out)
+}
+
+func statusOf(issue map[string]interface{}) string {
+	fields, _ := issue["fields"].(map[string]interface{})
+	status, _ := fields["status"].(map[string]interface{})
+	name, _ := status["name"].(string)
+	return name
+}
+
+// notify runs the configured Signal command, eg: to trigger a desktop
+// notification when the query result changes.
+func (w *Watcher) notify() error {
+	cmd := exec.Command("sh", "-c", w.cfg.Signal)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("signal command failed: %s: %s", err, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package watch
+
+import "testing"
+
+func issueWithStatus(key, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"key": key,
+		"fields": map[string]interface{}{
+			"status": map[string]interface{}{"name": status},
+		},
+	}
+}
+
+func TestWatcherDiff(t *testing.T) {
+	w := &Watcher{
+		prev: map[string]map[string]interface{}{
+			"A-1": issueWithStatus("A-1", "Open"),
+			"A-2": issueWithStatus("A-2", "Open"),
+		},
+	}
+
+	current := map[string]map[string]interface{}{
+		// A-1 unchanged
+		"A-1": issueWithStatus("A-1", "Open"),
+		// A-2 transitioned
+		"A-2": issueWithStatus("A-2", "Closed"),
+		// A-3 added
+		"A-3": issueWithStatus("A-3", "Open"),
+	}
+	diff := w.diff(current)
+
+	if got, want := diff.added, []string{"A-3"}; !sameSet(got, want) {
+		t.Errorf("added = %v, want %v", got, want)
+	}
+	if got, want := diff.transitioned, []string{"A-2"}; !sameSet(got, want) {
+		t.Errorf("transitioned = %v, want %v", got, want)
+	}
+	if len(diff.removed) != 0 {
+		t.Errorf("removed = %v, want none", diff.removed)
+	}
+}
+
+func TestWatcherDiffRemoved(t *testing.T) {
+	w := &Watcher{
+		prev: map[string]map[string]interface{}{
+			"A-1": issueWithStatus("A-1", "Open"),
+			"A-2": issueWithStatus("A-2", "Open"),
+		},
+	}
+	current := map[string]map[string]interface{}{
+		"A-1": issueWithStatus("A-1", "Open"),
+	}
+	diff := w.diff(current)
+
+	if len(diff.added) != 0 || len(diff.transitioned) != 0 {
+		t.Fatalf("unexpected diff: %#v", diff)
+	}
+	if got, want := diff.removed, []string{"A-2"}; !sameSet(got, want) {
+		t.Errorf("removed = %v, want %v", got, want)
+	}
+}
+
+func TestWatcherDiffEmpty(t *testing.T) {
+	w := &Watcher{prev: map[string]map[string]interface{}{}}
+	diff := w.diff(map[string]map[string]interface{}{})
+	if !diff.empty() {
+		t.Errorf("diff = %#v, want empty", diff)
+	}
+}
+
+func sameSet(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[string]bool, len(want))
+	for _, v := range want {
+		seen[v] = true
+	}
+	for _, v := range got {
+		if !seen[v] {
+			return false
+		}
+	}
+	return true
+}